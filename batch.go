@@ -0,0 +1,142 @@
+package cmdp
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+/*
+BatchOpts configures StartBatch.
+*/
+type BatchOpts struct {
+	/*
+		ContinueOnError (optional) - when false (the default) StartBatch stops at
+		the first command that fails to select, parse, or run.  When true it
+		keeps executing the remaining lines and reports every failure in the
+		returned results.
+	*/
+	ContinueOnError bool
+}
+
+/*
+BatchResult records the outcome of a single line processed by StartBatch.
+*/
+type BatchResult struct {
+	CmdLn string
+	Err   error
+}
+
+/*
+StartBatch runs the same select/parse/run pipeline as the interactive
+processor, but synchronously: one line of r at a time, in order, with no
+goroutine or shutdown channel involved.  Blank lines and lines starting
+with '#' are skipped.  It stops at the first error unless
+opts.ContinueOnError is set, and always returns the per-line results
+accumulated up to that point.
+*/
+func StartBatch(cds []Cdef, r io.Reader, opts BatchOpts) (results []BatchResult, err error) {
+	cs, err := validate(cds)
+	if err != nil {
+		return nil, err
+	}
+	scn := bufio.NewScanner(r)
+	for scn.Scan() {
+		cmdLn := strings.TrimSpace(scn.Text())
+		if cmdLn == "" || strings.HasPrefix(cmdLn, "#") {
+			continue
+		}
+		lnErr := directiveOrRun(cs.cmmds, cmdLn)
+		results = append(results, BatchResult{CmdLn: cmdLn, Err: lnErr})
+		if lnErr != nil && !opts.ContinueOnError {
+			return results, lnErr
+		}
+	}
+	if err := scn.Err(); err != nil {
+		return results, err
+	}
+	return results, nil
+}
+
+/*
+StartMarkdownScript extracts every fenced code block labeled blockLabel
+(a ` ```blockLabel ` ... ` ``` ` pair) from r and feeds its non-blank,
+non-'#'-prefixed lines to StartBatch in order, stopping on the first
+error.  This lets tutorial-style markdown documentation double as an
+executable, testable script without pulling in a separate tool.
+*/
+func StartMarkdownScript(cds []Cdef, r io.Reader, blockLabel string) error {
+	lines, err := markdownExtract(r, blockLabel)
+	if err != nil {
+		return err
+	}
+	_, err = StartBatch(cds, strings.NewReader(strings.Join(lines, "\n")), BatchOpts{})
+	return err
+}
+
+// markdownExtract scans r for fenced code blocks opened by "```"+blockLabel
+// and closed by a bare "```", tracking fence state with a single flag. A
+// fence marker only counts when it isn't itself part of a markdown
+// four-space-indented code block - e.g. a tutorial showing the fence
+// syntax as a quoted example - so such blocks are never mistaken for a
+// real fence and executed.
+func markdownExtract(r io.Reader, blockLabel string) (lines []string, err error) {
+	fenceOpen := "```" + blockLabel
+	inFence := false
+	scn := bufio.NewScanner(r)
+	for scn.Scan() {
+		line := scn.Text()
+		trimmed := strings.TrimSpace(line)
+		if !inFence {
+			if !indentedCodeBlock(line) && trimmed == fenceOpen {
+				inFence = true
+			}
+			continue
+		}
+		if !indentedCodeBlock(line) && trimmed == "```" {
+			inFence = false
+			continue
+		}
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		lines = append(lines, trimmed)
+	}
+	return lines, scn.Err()
+}
+
+// indentedCodeBlock reports whether line carries markdown's own indented-
+// code-block marker: four or more leading spaces, or a leading tab.
+func indentedCodeBlock(line string) bool {
+	if strings.HasPrefix(line, "\t") {
+		return true
+	}
+	n := 0
+	for n < len(line) && line[n] == ' ' {
+		n++
+	}
+	return n >= 4
+}
+
+// directiveOrRun recognizes the tutorial directives "@sleep <duration>" and
+// "@echo <text>" before falling back to the normal command pipeline, so
+// runnable markdown can pace itself and narrate without defining real
+// commands for housekeeping.
+func directiveOrRun(cmds []cdef, cmdLn string) error {
+	switch {
+	case strings.HasPrefix(cmdLn, "@sleep "):
+		d, err := time.ParseDuration(strings.TrimSpace(strings.TrimPrefix(cmdLn, "@sleep ")))
+		if err != nil {
+			return fmt.Errorf("@sleep: %s", err)
+		}
+		time.Sleep(d)
+		return nil
+	case strings.HasPrefix(cmdLn, "@echo "):
+		fmt.Println(strings.TrimPrefix(cmdLn, "@echo "))
+		return nil
+	default:
+		return cmdParseRunErr(cmds, cmdLn)
+	}
+}
@@ -0,0 +1,141 @@
+package cmdp
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type recordRun struct {
+	seen *[]string
+}
+
+func (r recordRun) Run(args []string) error {
+	*r.seen = append(*r.seen, strings.Join(args, ","))
+	return nil
+}
+
+func Test_StartBatch_RunsEachLineInOrder(t *testing.T) {
+	assrt := assert.New(t)
+	var seen []string
+	conCmds := []Cdef{
+		{NmShort: "r", NmLong: "run", Run: recordRun{&seen}, Parse: ParseShlex(), Help: "run"},
+	}
+	results, err := StartBatch(conCmds, strings.NewReader("run first\n# a comment\n\nrun second\n"), BatchOpts{})
+	assrt.Nil(err)
+	assrt.Len(results, 2)
+	assrt.Equal([]string{"first", "second"}, seen)
+}
+
+func Test_StartBatch_StopsOnFirstErrorByDefault(t *testing.T) {
+	assrt := assert.New(t)
+	conCmds := []Cdef{
+		{NmShort: "r", NmLong: "run", Run: runNone{}, Parse: ParseNone(), Help: "run"},
+	}
+	results, err := StartBatch(conCmds, strings.NewReader("bogus\nrun\n"), BatchOpts{})
+	assrt.NotNil(err)
+	assrt.Len(results, 1)
+}
+
+func Test_StartBatch_ContinuesOnErrorWhenRequested(t *testing.T) {
+	assrt := assert.New(t)
+	conCmds := []Cdef{
+		{NmShort: "r", NmLong: "run", Run: runNone{}, Parse: ParseNone(), Help: "run"},
+	}
+	results, err := StartBatch(conCmds, strings.NewReader("bogus\nrun\n"), BatchOpts{ContinueOnError: true})
+	assrt.Nil(err)
+	assrt.Len(results, 2)
+	assrt.NotNil(results[0].Err)
+	assrt.Nil(results[1].Err)
+}
+
+func Test_MarkdownExtract_IgnoresIndentedCodeBlock(t *testing.T) {
+	assrt := assert.New(t)
+	lines, err := markdownExtract(strings.NewReader("Some text\n\n    ```cmdp\n    run\n    ```\n"), "cmdp")
+	assrt.Nil(err)
+	assrt.Nil(lines)
+}
+
+func Test_MarkdownExtract_IgnoresTabIndentedCodeBlock(t *testing.T) {
+	assrt := assert.New(t)
+	lines, err := markdownExtract(strings.NewReader("Some text\n\n\t```cmdp\n\trun\n\t```\n"), "cmdp")
+	assrt.Nil(err)
+	assrt.Nil(lines)
+}
+
+func Test_Directive_EchoWritesText(t *testing.T) {
+	assrt := assert.New(t)
+	conCmds := []Cdef{
+		{NmShort: "r", NmLong: "run", Run: runNone{}, Parse: ParseNone(), Help: "run"},
+	}
+	out := captureStdout(t, func() {
+		results, err := StartBatch(conCmds, strings.NewReader("@echo hello there\n"), BatchOpts{})
+		assrt.Nil(err)
+		assrt.Len(results, 1)
+		assrt.Nil(results[0].Err)
+	})
+	assrt.Equal("hello there\n", out)
+}
+
+// captureStdout redirects os.Stdout for the duration of fn and returns
+// everything written to it, for directives like @echo that write there
+// directly rather than through a configurable writer.
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	orig := os.Stdout
+	os.Stdout = w
+	fn()
+	os.Stdout = orig
+	w.Close()
+	var buf bytes.Buffer
+	if _, err := io.Copy(&buf, r); err != nil {
+		t.Fatal(err)
+	}
+	return buf.String()
+}
+
+func Test_Directive_SleepParsesDuration(t *testing.T) {
+	assrt := assert.New(t)
+	conCmds := []Cdef{
+		{NmShort: "r", NmLong: "run", Run: runNone{}, Parse: ParseNone(), Help: "run"},
+	}
+	start := time.Now()
+	results, err := StartBatch(conCmds, strings.NewReader("@sleep 10ms\n"), BatchOpts{})
+	assrt.Nil(err)
+	assrt.Len(results, 1)
+	assrt.Nil(results[0].Err)
+	assrt.GreaterOrEqual(time.Since(start), 10*time.Millisecond)
+}
+
+func Test_Directive_SleepRejectsMalformedDuration(t *testing.T) {
+	assrt := assert.New(t)
+	conCmds := []Cdef{
+		{NmShort: "r", NmLong: "run", Run: runNone{}, Parse: ParseNone(), Help: "run"},
+	}
+	results, err := StartBatch(conCmds, strings.NewReader("@sleep not-a-duration\n"), BatchOpts{})
+	assrt.NotNil(err)
+	assrt.Len(results, 1)
+	assrt.NotNil(results[0].Err)
+	assrt.Contains(results[0].Err.Error(), "@sleep")
+}
+
+func Test_StartMarkdownScript_ExtractsLabeledFence(t *testing.T) {
+	assrt := assert.New(t)
+	var seen []string
+	conCmds := []Cdef{
+		{NmShort: "r", NmLong: "run", Run: recordRun{&seen}, Parse: ParseNone(), Help: "run"},
+	}
+	md := "# Tutorial\n\nSome prose.\n\n```cmdp\nrun\n# skip me\nrun\n```\n\n```bash\nrun\n```\n"
+	err := StartMarkdownScript(conCmds, strings.NewReader(md), "cmdp")
+	assrt.Nil(err)
+	assrt.Len(seen, 2)
+}
@@ -51,6 +51,15 @@ type Cdef struct {
 		Run binds the text command to its go counterpart using a closure.
 	*/
 	Run Runner
+	/*
+		Sub (optional) - A nested set of Cdef's that turns this entry into a command
+		group instead of a leaf command, e.g. 'repo' with Sub entries 'add' and 'list'
+		lets a user type 'repo add ...'.  NmShort/NmLong only need to be unique among
+		their siblings, not globally.  A Cdef that specifies Sub must leave Run nil;
+		only a leaf command is ever invoked.  A leaf's Parse receives the leaf's own
+		name followed by its arguments; the parent group names are not included.
+	*/
+	Sub []Cdef
 }
 
 /*
@@ -123,6 +132,7 @@ type cdef struct {
 	Cdef
 	nmShortL string
 	nmLongL  string
+	sub      []cdef
 }
 
 type cmds struct {
@@ -133,20 +143,68 @@ func validate(cds []Cdef) (cs *cmds, errs error) {
 	if len(cds) < 1 {
 		return nil, fmt.Errorf("commands not defined")
 	}
-	cs = &cmds{cmmds: make([]cdef, len(cds))}
+	cmmds, vErrs := cdefSliceVerify(cds, "")
+	if vErrs != nil {
+		errs = errorsConcat(errs, vErrs)
+	}
+	cs = &cmds{cmmds: cmmds}
+	helpPatch(cs.cmmds, cs)
+	return cs, errs
+}
+
+// helpPatch walks every level of the command tree, including Sub
+// descendants, so a RunHelp() used anywhere - not just at the root - gets
+// wired up to the full command set before it can be invoked.
+func helpPatch(cmmds []cdef, cs *cmds) {
+	for i := range cmmds {
+		if hlp, ok := cmmds[i].Run.(*helpRunner); ok {
+			hlp.patch(cs)
+		}
+		if len(cmmds[i].sub) > 0 {
+			helpPatch(cmmds[i].sub, cs)
+		}
+	}
+}
+
+// cdefSliceVerify validates a sibling level of Cdef's, enforcing name
+// uniqueness among those siblings only, and recurses into any Sub trees.
+func cdefSliceVerify(cds []Cdef, path string) (cmmds []cdef, errs error) {
+	cmmds = make([]cdef, len(cds))
+	nmSeen := map[string]bool{}
 	for i, cPub := range cds {
-		if err := cdefVerify(cPub, strconv.Itoa(i)); err != nil {
+		altNm := path + strconv.Itoa(i)
+		if err := cdefVerify(cPub, altNm); err != nil {
 			errs = errorsConcat(errs, err)
 			continue
 		}
-		cs.cmmds[i].Cdef = cPub
-		cs.cmmds[i].nmShortL = strings.ToLower(cPub.NmShort)
-		cs.cmmds[i].nmLongL = strings.ToLower(cPub.NmLong)
-		if hlp, ok := cs.cmmds[i].Run.(*helpRunner); ok {
-			hlp.patch(cs)
+		nmShortL := strings.ToLower(cPub.NmShort)
+		nmLongL := strings.ToLower(cPub.NmLong)
+		if nmShortL != "" && nmSeen[nmShortL] {
+			errs = errorsConcat(errs, fmt.Errorf("duplicate command name '%s' among siblings of '%s'", cPub.NmShort, siblingRef(path)))
+		}
+		if nmSeen[nmLongL] {
+			errs = errorsConcat(errs, fmt.Errorf("duplicate command name '%s' among siblings of '%s'", cPub.NmLong, siblingRef(path)))
+		}
+		nmSeen[nmShortL], nmSeen[nmLongL] = true, true
+		cmmds[i].Cdef = cPub
+		cmmds[i].nmShortL = nmShortL
+		cmmds[i].nmLongL = nmLongL
+		if len(cPub.Sub) > 0 {
+			sub, subErrs := cdefSliceVerify(cPub.Sub, cPub.NmLong+" ")
+			cmmds[i].sub = sub
+			if subErrs != nil {
+				errs = errorsConcat(errs, subErrs)
+			}
 		}
 	}
-	return cs, errs
+	return cmmds, errs
+}
+
+func siblingRef(path string) string {
+	if path == "" {
+		return "<root>"
+	}
+	return strings.TrimSpace(path)
 }
 
 type pn struct {
@@ -156,22 +214,6 @@ func (pn) Parse(string) ([]string, error) {
 	return nil, nil
 }
 
-type helpRunner struct {
-	cs *cmds
-}
-
-func (hr *helpRunner) Run(args []string) error {
-	fmt.Println("Help :")
-	for _, c := range hr.cs.cmmds {
-		fmt.Printf("%s,%s %s\n", c.NmShort, c.NmLong, c.ArgDesc)
-		fmt.Printf("    %s\n", c.Help)
-	}
-	return nil
-}
-func (hr *helpRunner) patch(cs *cmds) {
-	hr.cs = cs
-}
-
 func cdefVerify(c Cdef, altNm string) (errs error) {
 	cdefRef := c.NmLong
 	if c.NmLong == "" {
@@ -180,11 +222,17 @@ func cdefVerify(c Cdef, altNm string) (errs error) {
 	} else if len(c.NmShort) > len(c.NmLong) {
 		errs = errorsConcat(errs, fmt.Errorf("Please specify a short name whose length doesn't exceed its corresponding long one for: %s", cdefRef))
 	}
-	if c.Parse == nil {
-		errs = errorsConcat(errs, fmt.Errorf("Please specify a Parse function for command: %s", cdefRef))
-	}
-	if c.Run == nil {
-		errs = errorsConcat(errs, fmt.Errorf("Please specify a Run function for command: %s", cdefRef))
+	if len(c.Sub) > 0 {
+		if c.Run != nil {
+			errs = errorsConcat(errs, fmt.Errorf("command: %s must not specify both Sub and Run - only leaf commands run", cdefRef))
+		}
+	} else {
+		if c.Parse == nil {
+			errs = errorsConcat(errs, fmt.Errorf("Please specify a Parse function for command: %s", cdefRef))
+		}
+		if c.Run == nil {
+			errs = errorsConcat(errs, fmt.Errorf("Please specify a Run function for command: %s", cdefRef))
+		}
 	}
 	if c.Help == "" {
 		errs = errorsConcat(errs, fmt.Errorf("Please specify a Help text for command: %s", cdefRef))
@@ -237,22 +285,25 @@ func responseFetch(resp chan<- string, rCmdLn *bufio.Reader) {
 	}
 }
 func cmdParseRun(cmds []cdef, cmdLn string) {
-	cmdNm, cmdArg := cmdNormalize(cmdLn)
-	cmd, err := cmdSelect(cmds, cmdNm)
-	if err != nil {
+	if err := cmdParseRunErr(cmds, cmdLn); err != nil {
 		fmt.Fprintf(os.Stderr, "%s\n", err)
-		return
 	}
-	var args []string
-	args, err = cmd.Parse.Parse(cmdNm + " " + cmdArg)
+}
+
+// cmdParseRunErr selects, parses, and runs a single command line, returning
+// whatever error surfaced instead of writing it to STDERR directly so
+// callers like StartBatch can collect it.
+func cmdParseRunErr(cmds []cdef, cmdLn string) error {
+	cmdNm, cmdArg := cmdNormalize(cmdLn)
+	cmd, leafNm, leafArg, err := cmdSelect(cmds, cmdNm, cmdArg)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "%s\n", err)
-		return
+		return err
 	}
-	if err := cmd.Run.Run(args); err != nil {
-		fmt.Fprintf(os.Stderr, "%s\n", err)
-		return
+	args, err := cmd.Parse.Parse(leafNm + " " + leafArg)
+	if err != nil {
+		return err
 	}
+	return cmd.Run.Run(args)
 }
 func cmdNormalize(cmdln string) (cmdNm string, cmdArg string) {
 	cmdln = strings.TrimSpace(cmdln)
@@ -264,11 +315,33 @@ func cmdNormalize(cmdln string) (cmdNm string, cmdArg string) {
 	}
 	return cmdNm, cmdArg
 }
-func cmdSelect(cmds []cdef, cmdNm string) (cdef, error) {
+func cmdLookup(cmds []cdef, cmdNm string) (cdef, bool) {
 	for _, c := range cmds {
 		if cmdNm == c.nmShortL || cmdNm == c.nmLongL {
-			return c, nil
+			return c, true
 		}
 	}
-	return cdef{}, fmt.Errorf("Error: unknown command: '%s' - try 'h' for help\n", cmdNm)
+	return cdef{}, false
+}
+
+// cmdSelect descends a command's Sub tree, consuming one whitespace-
+// delimited token per level, until it reaches a leaf command.  It returns
+// the leaf, the token that matched it, and whatever argument text remains
+// to be parsed.
+func cmdSelect(cmds []cdef, cmdNm string, cmdArg string) (cdef, string, string, error) {
+	c, ok := cmdLookup(cmds, cmdNm)
+	if !ok {
+		return cdef{}, "", "", fmt.Errorf("Error: unknown command: '%s' - try 'h' for help\n", cmdNm)
+	}
+	if len(c.sub) == 0 {
+		return c, cmdNm, cmdArg, nil
+	}
+	childNm, childArg := cmdNormalize(cmdArg)
+	if childNm == "" {
+		return cdef{}, "", "", fmt.Errorf("Error: '%s' requires a subcommand - try '%s help'\n", c.NmLong, c.NmLong)
+	}
+	if _, ok := cmdLookup(c.sub, childNm); !ok {
+		return cdef{}, "", "", fmt.Errorf("unknown subcommand '%s' under '%s' - try '%s help'", childNm, c.NmLong, c.NmLong)
+	}
+	return cmdSelect(c.sub, childNm, childArg)
 }
@@ -0,0 +1,105 @@
+package cmdp
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"golang.org/x/term"
+)
+
+type helpRunner struct {
+	cs *cmds
+}
+
+/*
+Run prints a help entry for every command, aligning where each Help text
+starts regardless of how long NmShort/NmLong/ArgDesc are, and word-wraps
+Help text to the detected terminal width (capped at 100 columns) so long
+descriptions don't run off screen.
+*/
+func (hr *helpRunner) Run(args []string) error {
+	fmt.Println("Help :")
+	entries := helpCollect(hr.cs.cmmds, 0)
+	headerW := 0
+	for _, e := range entries {
+		if w := e.width(); w > headerW {
+			headerW = w
+		}
+	}
+	wrapW := termWidth() - headerW - 1
+	if wrapW < 20 {
+		wrapW = 20
+	}
+	for _, e := range entries {
+		e.print(headerW, wrapW)
+	}
+	return nil
+}
+func (hr *helpRunner) patch(cs *cmds) {
+	hr.cs = cs
+}
+
+// helpEntry is one flattened row of the command tree, kept alongside its
+// depth so Run can compute a single header column width across every level.
+type helpEntry struct {
+	depth  int
+	header string
+	help   string
+}
+
+// width returns the rune-aware column width this entry's indent + header
+// occupy, used to line up every row's Help text at the same column.
+func (e helpEntry) width() int {
+	return e.depth*4 + stringWidth(e.header)
+}
+
+func (e helpEntry) print(headerW, wrapW int) {
+	indent := strings.Repeat("    ", e.depth)
+	pad := headerW - e.width()
+	lines := wordWrap(e.help, wrapW)
+	fmt.Printf("%s%s%s %s\n", indent, e.header, strings.Repeat(" ", pad), lines[0])
+	for _, l := range lines[1:] {
+		fmt.Printf("%s%s\n", strings.Repeat(" ", headerW+1), l)
+	}
+}
+
+// helpCollect flattens a command tree into display order, depth-first, so
+// RunHelp can compute one aligned column width across every Sub level.
+func helpCollect(cmmds []cdef, depth int) []helpEntry {
+	var entries []helpEntry
+	for _, c := range cmmds {
+		entries = append(entries, helpEntry{
+			depth:  depth,
+			header: fmt.Sprintf("%s,%s %s", c.NmShort, c.NmLong, c.ArgDesc),
+			help:   c.Help,
+		})
+		if len(c.sub) > 0 {
+			entries = append(entries, helpCollect(c.sub, depth+1)...)
+		}
+	}
+	return entries
+}
+
+var (
+	termWidthOnce sync.Once
+	termWidthVal  int
+)
+
+// termWidth detects the terminal width once per process, falling back to
+// 80 columns when it can't be determined (e.g. stdout isn't a terminal),
+// and caps it at 100 so help text doesn't stretch across a wide screen.
+func termWidth() int {
+	termWidthOnce.Do(func() {
+		w, _, err := term.GetSize(int(os.Stdout.Fd()))
+		if err != nil || w <= 0 {
+			w = 80
+		}
+		if w > 100 {
+			w = 100
+		}
+		termWidthVal = w
+	})
+	return termWidthVal
+}
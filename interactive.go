@@ -0,0 +1,159 @@
+package cmdp
+
+import (
+	"bufio"
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+/*
+InteractiveOpts configures StartInteractive's line editor.
+*/
+type InteractiveOpts struct {
+	/*
+		Prompt (optional) - text displayed before each input line.  Defaults to "> ".
+	*/
+	Prompt string
+	/*
+		HistoryFile (optional) - path used to persist command history across
+		sessions.  Leave empty to keep history in memory only.
+	*/
+	HistoryFile string
+	/*
+		HistorySize (optional) - maximum number of lines retained in history.
+		Defaults to 500.
+	*/
+	HistorySize int
+}
+
+/*
+Completer lets a Cdef supply per-command argument completions.  Implement it
+on the same value assigned to Cdef.Run to enable TAB-completion of that
+command's arguments; command names themselves are always completed against
+NmShort/NmLong without requiring a Completer.
+*/
+type Completer interface {
+	Complete(prefix string, args []string) []string
+}
+
+/*
+StartInteractive behaves like Start but, when stdin is a terminal, drives a
+line editor offering up/down history navigation, Ctrl-C line abort, Ctrl-D
+cooperative shutdown, and TAB-completion instead of cmdp's plain
+line-buffered reader.  When stdin isn't a terminal (a pipe or redirected
+file, as used by tests) it falls back to Start's behavior so existing
+callers are unaffected.
+*/
+func StartInteractive(cds []Cdef, opts InteractiveOpts) (shutdown chan bool, err error) {
+	if opts.Prompt == "" {
+		opts.Prompt = "> "
+	}
+	if opts.HistorySize <= 0 {
+		opts.HistorySize = 500
+	}
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return start(cds, bufio.NewReader(os.Stdin))
+	}
+	var cs *cmds
+	cs, err = validate(cds)
+	if err != nil {
+		return nil, err
+	}
+	le := newLineEditor(os.Stdin, os.Stdout, opts)
+	le.complete = func(line string) []string {
+		return completeLine(cs.cmmds, line)
+	}
+	shutdown = make(chan bool)
+	go processCmdLnInteractive(cs.cmmds, shutdown, le)
+	return shutdown, nil
+}
+
+func processCmdLnInteractive(cmds []cdef, shutdown chan bool, le *lineEditor) {
+	defer close(shutdown)
+	resp := responseConfigInteractive(le)
+	for {
+		select {
+		case cmdLn, ok := <-resp:
+			if !ok {
+				return
+			}
+			cmdParseRun(cmds, cmdLn)
+		case sd := <-shutdown:
+			if sd {
+				return
+			}
+		}
+	}
+}
+
+func responseConfigInteractive(le *lineEditor) (response <-chan string) {
+	resp := make(chan string)
+	go responseFetchInteractive(resp, le)
+	return resp
+}
+
+// Mirrors responseFetch: survives a shutdown request until the raw-mode
+// read finally unblocks (Ctrl-D or an i/o error), ignoring that error.
+func responseFetchInteractive(resp chan<- string, le *lineEditor) {
+	defer close(resp)
+	for {
+		line, err := le.ReadLine()
+		if err == errAbortLine {
+			continue
+		}
+		if err != nil {
+			return
+		}
+		resp <- line
+	}
+}
+
+// completeLine resolves TAB-completion candidates for the text entered so
+// far: command names at the current word, descending one level per Sub
+// token consumed, down to the matched leaf command's own Completer (if
+// any) for its arguments.
+func completeLine(cmds []cdef, line string) []string {
+	fields := strings.Fields(line)
+	trailingSpace := strings.HasSuffix(line, " ")
+	return completeFields(cmds, fields, trailingSpace)
+}
+
+func completeFields(cmds []cdef, fields []string, trailingSpace bool) []string {
+	if len(fields) == 0 || (len(fields) == 1 && !trailingSpace) {
+		prefix := ""
+		if len(fields) == 1 {
+			prefix = strings.ToLower(fields[0])
+		}
+		var out []string
+		for _, c := range cmds {
+			for _, nm := range []string{c.NmShort, c.NmLong} {
+				if nm != "" && strings.HasPrefix(strings.ToLower(nm), prefix) {
+					out = append(out, nm)
+				}
+			}
+		}
+		return out
+	}
+	cmdNm := strings.ToLower(fields[0])
+	c, ok := cmdLookup(cmds, cmdNm)
+	if !ok {
+		return nil
+	}
+	rest := fields[1:]
+	if len(c.sub) > 0 {
+		return completeFields(c.sub, rest, trailingSpace)
+	}
+	comp, ok := c.Run.(Completer)
+	if !ok {
+		return nil
+	}
+	args := rest
+	prefix := ""
+	if !trailingSpace && len(args) > 0 {
+		prefix = args[len(args)-1]
+		args = args[:len(args)-1]
+	}
+	return comp.Complete(prefix, args)
+}
@@ -0,0 +1,81 @@
+package cmdp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type completerStub struct {
+	runNone
+}
+
+func (completerStub) Complete(prefix string, args []string) []string {
+	if prefix == "ho" {
+		return []string{"home"}
+	}
+	return nil
+}
+
+func Test_CompleteLine_CommandName(t *testing.T) {
+	assrt := assert.New(t)
+	cs, err := validate([]Cdef{
+		{NmShort: "r", NmLong: "run", Run: runNone{}, Parse: ParseNone(), Help: "run"},
+		{NmShort: "h", NmLong: "help", Run: runNone{}, Parse: ParseNone(), Help: "help"},
+	})
+	assrt.Nil(err)
+	assrt.ElementsMatch([]string{"r", "run"}, completeLine(cs.cmmds, "r"))
+}
+
+func Test_CompleteLine_DelegatesToCompleter(t *testing.T) {
+	assrt := assert.New(t)
+	cs, err := validate([]Cdef{
+		{NmShort: "r", NmLong: "run", Run: completerStub{}, Parse: ParseNone(), Help: "run"},
+	})
+	assrt.Nil(err)
+	assrt.Equal([]string{"home"}, completeLine(cs.cmmds, "run ho"))
+}
+
+func Test_CompleteLine_ListsSubcommandsOfAParent(t *testing.T) {
+	assrt := assert.New(t)
+	cs, err := validate([]Cdef{
+		{NmLong: "repo", Help: "repo commands", Sub: []Cdef{
+			{NmShort: "a", NmLong: "add", Run: runNone{}, Parse: ParseNone(), Help: "add a repo"},
+			{NmShort: "l", NmLong: "list", Run: runNone{}, Parse: ParseNone(), Help: "list repos"},
+		}},
+	})
+	assrt.Nil(err)
+	assrt.ElementsMatch([]string{"a", "add", "l", "list"}, completeLine(cs.cmmds, "repo "))
+}
+
+func Test_CompleteLine_FiltersSubcommandsByPrefix(t *testing.T) {
+	assrt := assert.New(t)
+	cs, err := validate([]Cdef{
+		{NmLong: "repo", Help: "repo commands", Sub: []Cdef{
+			{NmShort: "a", NmLong: "add", Run: runNone{}, Parse: ParseNone(), Help: "add a repo"},
+			{NmShort: "l", NmLong: "list", Run: runNone{}, Parse: ParseNone(), Help: "list repos"},
+		}},
+	})
+	assrt.Nil(err)
+	assrt.Equal([]string{"add"}, completeLine(cs.cmmds, "repo ad"))
+}
+
+func Test_CompleteLine_DelegatesToGrandchildCompleter(t *testing.T) {
+	assrt := assert.New(t)
+	cs, err := validate([]Cdef{
+		{NmLong: "repo", Help: "repo commands", Sub: []Cdef{
+			{NmShort: "a", NmLong: "add", Run: completerStub{}, Parse: ParseNone(), Help: "add a repo"},
+		}},
+	})
+	assrt.Nil(err)
+	assrt.Equal([]string{"home"}, completeLine(cs.cmmds, "repo add ho"))
+}
+
+func Test_CompleteLine_UnknownCommandYieldsNoCandidates(t *testing.T) {
+	assrt := assert.New(t)
+	cs, err := validate([]Cdef{
+		{NmShort: "r", NmLong: "run", Run: runNone{}, Parse: ParseNone(), Help: "run"},
+	})
+	assrt.Nil(err)
+	assrt.Nil(completeLine(cs.cmmds, "bogus arg"))
+}
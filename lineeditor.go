@@ -0,0 +1,204 @@
+package cmdp
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// errAbortLine signals the user pressed Ctrl-C while editing a line; the
+// partial line is discarded and the caller should issue a fresh prompt.
+var errAbortLine = fmt.Errorf("line aborted")
+
+// lineEditor drives a single raw-mode terminal, offering history
+// navigation and TAB-completion on top of a plain ReadLine call.
+type lineEditor struct {
+	f        *os.File
+	rdr      *bufio.Reader
+	out      io.Writer
+	prompt   string
+	history  []string
+	histMax  int
+	histFile string
+	histPos  int
+	complete func(line string) []string
+}
+
+func newLineEditor(f *os.File, out io.Writer, opts InteractiveOpts) *lineEditor {
+	le := &lineEditor{
+		f:        f,
+		rdr:      bufio.NewReader(f),
+		out:      out,
+		prompt:   opts.Prompt,
+		histMax:  opts.HistorySize,
+		histFile: opts.HistoryFile,
+	}
+	le.historyLoad()
+	return le
+}
+
+// ReadLine reads and edits a single line of input.  It returns errAbortLine
+// on Ctrl-C and io.EOF on Ctrl-D issued against an empty line.
+func (le *lineEditor) ReadLine() (line string, err error) {
+	oldState, err := term.MakeRaw(int(le.f.Fd()))
+	if err != nil {
+		return "", err
+	}
+	defer term.Restore(int(le.f.Fd()), oldState)
+
+	var buf []rune
+	cursor := 0
+	le.histPos = len(le.history)
+	fmt.Fprint(le.out, le.prompt)
+	for {
+		r, _, err := le.rdr.ReadRune()
+		if err != nil {
+			return "", err
+		}
+		switch r {
+		case '\r', '\n':
+			fmt.Fprint(le.out, "\r\n")
+			line := string(buf)
+			le.historyAdd(line)
+			return line, nil
+		case 3: // Ctrl-C
+			fmt.Fprint(le.out, "\r\n")
+			return "", errAbortLine
+		case 4: // Ctrl-D
+			if len(buf) == 0 {
+				fmt.Fprint(le.out, "\r\n")
+				return "", io.EOF
+			}
+		case 127, 8: // Backspace
+			if cursor > 0 {
+				buf = append(buf[:cursor-1], buf[cursor:]...)
+				cursor--
+				le.redraw(buf, cursor)
+			}
+		case '\t':
+			buf, cursor = le.completeWord(buf, cursor)
+			le.redraw(buf, cursor)
+		case 27: // ESC - only arrow keys (CSI A/B/C/D) are recognized
+			b1, _, e1 := le.rdr.ReadRune()
+			b2, _, e2 := le.rdr.ReadRune()
+			if e1 != nil || e2 != nil || b1 != '[' {
+				continue
+			}
+			switch b2 {
+			case 'A':
+				buf, cursor = le.historyPrev(buf)
+			case 'B':
+				buf, cursor = le.historyNext(buf)
+			case 'C':
+				if cursor < len(buf) {
+					cursor++
+				}
+			case 'D':
+				if cursor > 0 {
+					cursor--
+				}
+			}
+			le.redraw(buf, cursor)
+		default:
+			buf = append(buf[:cursor], append([]rune{r}, buf[cursor:]...)...)
+			cursor++
+			le.redraw(buf, cursor)
+		}
+	}
+}
+
+// redraw clears the current line and repaints the prompt, buffer, and
+// cursor position.
+func (le *lineEditor) redraw(buf []rune, cursor int) {
+	fmt.Fprintf(le.out, "\r\x1b[K%s%s", le.prompt, string(buf))
+	if back := len(buf) - cursor; back > 0 {
+		fmt.Fprintf(le.out, "\x1b[%dD", back)
+	}
+}
+
+func (le *lineEditor) historyAdd(line string) {
+	if strings.TrimSpace(line) == "" {
+		return
+	}
+	le.history = append(le.history, line)
+	if len(le.history) > le.histMax {
+		le.history = le.history[len(le.history)-le.histMax:]
+	}
+	le.historySave()
+}
+
+func (le *lineEditor) historyPrev(cur []rune) ([]rune, int) {
+	if le.histPos == 0 {
+		return cur, len(cur)
+	}
+	le.histPos--
+	r := []rune(le.history[le.histPos])
+	return r, len(r)
+}
+
+func (le *lineEditor) historyNext(cur []rune) ([]rune, int) {
+	if le.histPos >= len(le.history) {
+		return cur, len(cur)
+	}
+	le.histPos++
+	if le.histPos == len(le.history) {
+		return nil, 0
+	}
+	r := []rune(le.history[le.histPos])
+	return r, len(r)
+}
+
+func (le *lineEditor) historyLoad() {
+	if le.histFile == "" {
+		return
+	}
+	data, err := os.ReadFile(le.histFile)
+	if err != nil {
+		return
+	}
+	for _, l := range strings.Split(string(data), "\n") {
+		if l != "" {
+			le.history = append(le.history, l)
+		}
+	}
+}
+
+func (le *lineEditor) historySave() {
+	if le.histFile == "" {
+		return
+	}
+	_ = os.WriteFile(le.histFile, []byte(strings.Join(le.history, "\n")+"\n"), 0o600)
+}
+
+// completeWord replaces the word under the cursor with the sole completion
+// candidate, or lists every candidate when more than one applies.
+func (le *lineEditor) completeWord(buf []rune, cursor int) ([]rune, int) {
+	if le.complete == nil {
+		return buf, cursor
+	}
+	head := string(buf[:cursor])
+	tail := buf[cursor:]
+	cands := le.complete(head)
+	if len(cands) == 0 {
+		return buf, cursor
+	}
+	if len(cands) > 1 {
+		fmt.Fprintf(le.out, "\r\n%s\r\n", strings.Join(cands, "  "))
+		return buf, cursor
+	}
+	fields := strings.Fields(head)
+	trailingSpace := strings.HasSuffix(head, " ")
+	var newHead string
+	if len(fields) == 0 || trailingSpace {
+		newHead = head + cands[0] + " "
+	} else {
+		fields[len(fields)-1] = cands[0]
+		newHead = strings.Join(fields, " ") + " "
+	}
+	newBuf := append([]rune(newHead), tail...)
+	return newBuf, len([]rune(newHead))
+}
@@ -0,0 +1,90 @@
+package cmdp
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_HistoryAdd_SkipsBlankLines(t *testing.T) {
+	assrt := assert.New(t)
+	le := &lineEditor{histMax: 10}
+	le.historyAdd("  ")
+	assrt.Empty(le.history)
+}
+
+func Test_HistoryAdd_TrimsOldestPastHistMax(t *testing.T) {
+	assrt := assert.New(t)
+	le := &lineEditor{histMax: 2}
+	le.historyAdd("one")
+	le.historyAdd("two")
+	le.historyAdd("three")
+	assrt.Equal([]string{"two", "three"}, le.history)
+}
+
+func Test_HistoryPrevNext_WalksRingAndStopsAtBoundaries(t *testing.T) {
+	assrt := assert.New(t)
+	le := &lineEditor{history: []string{"one", "two", "three"}, histMax: 10}
+	le.histPos = len(le.history)
+
+	buf, cursor := le.historyPrev(nil)
+	assrt.Equal("three", string(buf))
+	assrt.Equal(5, cursor)
+
+	buf, cursor = le.historyPrev(buf)
+	assrt.Equal("two", string(buf))
+	assrt.Equal(3, cursor)
+
+	buf, cursor = le.historyPrev(buf)
+	assrt.Equal("one", string(buf))
+	assrt.Equal(3, cursor)
+
+	// Already at the oldest entry - historyPrev leaves the buffer untouched.
+	buf, cursor = le.historyPrev(buf)
+	assrt.Equal("one", string(buf))
+	assrt.Equal(3, cursor)
+
+	buf, cursor = le.historyNext(buf)
+	assrt.Equal("two", string(buf))
+	assrt.Equal(3, cursor)
+
+	buf, cursor = le.historyNext(buf)
+	assrt.Equal("three", string(buf))
+	assrt.Equal(5, cursor)
+
+	// Walking past the newest entry clears the buffer back to empty input.
+	buf, cursor = le.historyNext(buf)
+	assrt.Empty(buf)
+	assrt.Equal(0, cursor)
+
+	// Already past the newest entry - historyNext leaves the buffer untouched.
+	buf, cursor = le.historyNext(buf)
+	assrt.Empty(buf)
+	assrt.Equal(0, cursor)
+}
+
+func Test_HistoryLoadSave_RoundTripsThroughFile(t *testing.T) {
+	assrt := assert.New(t)
+	histFile := filepath.Join(t.TempDir(), "history")
+
+	saver := &lineEditor{histFile: histFile, histMax: 10}
+	saver.historyAdd("one")
+	saver.historyAdd("two")
+
+	data, err := os.ReadFile(histFile)
+	assrt.Nil(err)
+	assrt.Equal("one\ntwo\n", string(data))
+
+	loader := &lineEditor{histFile: histFile, histMax: 10}
+	loader.historyLoad()
+	assrt.Equal([]string{"one", "two"}, loader.history)
+}
+
+func Test_HistoryLoad_MissingFileLeavesHistoryEmpty(t *testing.T) {
+	assrt := assert.New(t)
+	le := &lineEditor{histFile: filepath.Join(t.TempDir(), "absent"), histMax: 10}
+	le.historyLoad()
+	assrt.Empty(le.history)
+}
@@ -0,0 +1,209 @@
+package cmdp
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+/*
+Phase identifies which stage of the select/parse/run pipeline produced an
+error.
+*/
+type Phase int
+
+const (
+	PhaseSelect Phase = iota
+	PhaseParse
+	PhaseRun
+)
+
+func (p Phase) String() string {
+	switch p {
+	case PhaseSelect:
+		return "select"
+	case PhaseParse:
+		return "parse"
+	case PhaseRun:
+		return "run"
+	default:
+		return "unknown"
+	}
+}
+
+/*
+CmdError pairs a phase-tagged error with the command line that produced it.
+*/
+type CmdError struct {
+	CmdLn string
+	Phase Phase
+	Err   error
+}
+
+func (e CmdError) Error() string {
+	return fmt.Sprintf("%s: %s: %s", e.CmdLn, e.Phase, e.Err)
+}
+
+/*
+Options configures StartWithOptions.  Every field is optional; Stdin,
+Stdout, and Stderr default to the corresponding os.Std* stream and Prompt
+defaults to "".
+*/
+type Options struct {
+	Stdin  io.Reader
+	Stdout io.Writer
+	Stderr io.Writer
+	Prompt string
+	/*
+		OnError (optional) - called, in addition to writing to Stderr, whenever
+		a command line fails to select, parse, or run.
+	*/
+	OnError func(cmdLine string, phase Phase, err error)
+}
+
+/*
+Session is the handle returned by StartWithOptions for controlling and
+observing a running command processor.
+*/
+type Session struct {
+	shutdown chan bool
+	done     chan struct{}
+	errs     chan CmdError
+}
+
+/*
+Shutdown requests a cooperative shutdown and returns the channel that
+closes once the processor has finished.  It's safe to call even after the
+processor has already stopped on its own (e.g. Stdin hit EOF): the
+shutdown request is dropped rather than blocking forever on a goroutine
+that's no longer listening.
+*/
+func (s *Session) Shutdown() <-chan struct{} {
+	select {
+	case s.shutdown <- true:
+	case <-s.done:
+	}
+	return s.done
+}
+
+/*
+Wait blocks until the processor has finished, whether because Shutdown was
+called or its input was exhausted.
+*/
+func (s *Session) Wait() {
+	<-s.done
+}
+
+/*
+Errors returns a channel of structured, phase-tagged errors - an
+alternative to scraping Stderr when cmdp is embedded in a daemon or test.
+*/
+func (s *Session) Errors() <-chan CmdError {
+	return s.errs
+}
+
+/*
+StartWithOptions is the configurable counterpart to Start: it decouples
+the processor from os.Stdin/os.Stdout/os.Stderr so cmdp can be embedded
+inside another server or driven from a test, and it reports errors as
+structured CmdError values via Session.Errors() and opts.OnError in
+addition to writing them to opts.Stderr.
+*/
+func StartWithOptions(cds []Cdef, opts Options) (*Session, error) {
+	cs, err := validate(cds)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Stdin == nil {
+		opts.Stdin = os.Stdin
+	}
+	if opts.Stdout == nil {
+		opts.Stdout = os.Stdout
+	}
+	if opts.Stderr == nil {
+		opts.Stderr = os.Stderr
+	}
+	s := &Session{
+		shutdown: make(chan bool),
+		done:     make(chan struct{}),
+		errs:     make(chan CmdError, 32),
+	}
+	go processCmdLnOpt(cs.cmmds, s, opts)
+	return s, nil
+}
+
+func processCmdLnOpt(cmds []cdef, s *Session, opts Options) {
+	defer close(s.done)
+	defer close(s.errs)
+	resp := responseConfigOpt(bufio.NewReader(opts.Stdin), opts.Stdout, opts.Prompt)
+	for {
+		select {
+		case cmdLn, ok := <-resp:
+			if !ok {
+				return
+			}
+			cmdParseRunOpt(cmds, cmdLn, s, opts)
+		case sd := <-s.shutdown:
+			if sd {
+				return
+			}
+		}
+	}
+}
+
+func responseConfigOpt(rCmdLn *bufio.Reader, stdout io.Writer, prompt string) (response <-chan string) {
+	resp := make(chan string)
+	go responseFetchOpt(resp, rCmdLn, stdout, prompt)
+	return resp
+}
+
+// Mirrors responseFetch, but writes opts.Prompt before each read and
+// tolerates a nil-free shutdown of a non-std stream (e.g. an in-memory
+// reader used by a test) exhausting with io.EOF.
+func responseFetchOpt(resp chan<- string, rCmdLn *bufio.Reader, stdout io.Writer, prompt string) {
+	defer close(resp)
+	for {
+		if prompt != "" {
+			fmt.Fprint(stdout, prompt)
+		}
+		cmdLn, err := rCmdLn.ReadString('\n')
+		if err != nil {
+			break
+		}
+		resp <- cmdLn
+	}
+}
+
+func cmdParseRunOpt(cmds []cdef, cmdLn string, s *Session, opts Options) {
+	cmdLn = strings.TrimSpace(cmdLn)
+	cmdNm, cmdArg := cmdNormalize(cmdLn)
+	cmd, leafNm, leafArg, err := cmdSelect(cmds, cmdNm, cmdArg)
+	if err != nil {
+		reportErr(cmdLn, PhaseSelect, err, s, opts)
+		return
+	}
+	args, err := cmd.Parse.Parse(leafNm + " " + leafArg)
+	if err != nil {
+		reportErr(cmdLn, PhaseParse, err, s, opts)
+		return
+	}
+	if err := cmd.Run.Run(args); err != nil {
+		reportErr(cmdLn, PhaseRun, err, s, opts)
+	}
+}
+
+func reportErr(cmdLn string, phase Phase, err error, s *Session, opts Options) {
+	ce := CmdError{CmdLn: cmdLn, Phase: phase, Err: err}
+	fmt.Fprintf(opts.Stderr, "%s\n", ce)
+	if opts.OnError != nil {
+		opts.OnError(cmdLn, phase, err)
+	}
+	select {
+	case s.errs <- ce:
+	default:
+		// drop rather than block command processing when nobody is
+		// draining Errors()
+	}
+}
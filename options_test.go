@@ -0,0 +1,75 @@
+package cmdp
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_StartWithOptions_UsesConfiguredIO(t *testing.T) {
+	assrt := assert.New(t)
+	var seen []string
+	conCmds := []Cdef{
+		{NmShort: "r", NmLong: "run", Run: recordRun{&seen}, Parse: ParseNone(), Help: "run"},
+	}
+	var stderr bytes.Buffer
+	s, err := StartWithOptions(conCmds, Options{
+		Stdin:  strings.NewReader("bogus\nrun\n"),
+		Stdout: &bytes.Buffer{},
+		Stderr: &stderr,
+	})
+	assrt.Nil(err)
+	ce := <-s.Errors()
+	assrt.Equal(PhaseSelect, ce.Phase)
+	assrt.Equal("bogus", ce.CmdLn)
+	s.Wait()
+	assrt.Contains(stderr.String(), "bogus")
+	assrt.Equal([]string{""}, seen)
+}
+
+func Test_StartWithOptions_OnErrorCallback(t *testing.T) {
+	assrt := assert.New(t)
+	conCmds := []Cdef{
+		{NmShort: "r", NmLong: "run", Run: runNone{}, Parse: ParseNone(), Help: "run"},
+	}
+	called := make(chan Phase, 1)
+	s, err := StartWithOptions(conCmds, Options{
+		Stdin:  strings.NewReader("bogus\n"),
+		Stdout: &bytes.Buffer{},
+		Stderr: &bytes.Buffer{},
+		OnError: func(cmdLine string, phase Phase, err error) {
+			called <- phase
+		},
+	})
+	assrt.Nil(err)
+	assrt.Equal(PhaseSelect, <-called)
+	s.Wait()
+}
+
+func Test_StartWithOptions_ShutdownAfterNaturalEOFDoesNotHang(t *testing.T) {
+	assrt := assert.New(t)
+	conCmds := []Cdef{
+		{NmShort: "r", NmLong: "run", Run: runNone{}, Parse: ParseNone(), Help: "run"},
+	}
+	s, err := StartWithOptions(conCmds, Options{
+		Stdin:  strings.NewReader("run\n"),
+		Stdout: &bytes.Buffer{},
+		Stderr: &bytes.Buffer{},
+	})
+	assrt.Nil(err)
+	s.Wait()
+
+	done := make(chan struct{})
+	go func() {
+		<-s.Shutdown()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Shutdown blocked after the processor had already stopped on its own")
+	}
+}
@@ -0,0 +1,103 @@
+package cmdp
+
+import (
+	"strings"
+	"unicode"
+)
+
+// stringWidth returns the on-screen column width of s: CSI escape
+// sequences contribute nothing, combining marks contribute nothing, East
+// Asian Wide/Fullwidth runes contribute two columns, everything else one.
+func stringWidth(s string) int {
+	w := 0
+	for _, r := range stripCSI(s) {
+		w += runeWidth(r)
+	}
+	return w
+}
+
+func runeWidth(r rune) int {
+	if unicode.Is(unicode.Mn, r) || unicode.Is(unicode.Me, r) {
+		return 0
+	}
+	if isEastAsianWide(r) {
+		return 2
+	}
+	return 1
+}
+
+// stripCSI removes ANSI CSI escape sequences (ESC '[' followed by
+// parameter/intermediate bytes and a final byte) so they don't inflate a
+// measured width.
+func stripCSI(s string) string {
+	if !strings.ContainsRune(s, 0x1b) {
+		return s
+	}
+	rs := []rune(s)
+	var b strings.Builder
+	for i := 0; i < len(rs); i++ {
+		if rs[i] == 0x1b && i+1 < len(rs) && rs[i+1] == '[' {
+			j := i + 2
+			for j < len(rs) && rs[j] >= 0x20 && rs[j] <= 0x3f {
+				j++
+			}
+			if j < len(rs) {
+				j++ // consume the final byte (0x40-0x7e)
+			}
+			i = j - 1
+			continue
+		}
+		b.WriteRune(rs[i])
+	}
+	return b.String()
+}
+
+// isEastAsianWide reports whether r falls in one of the common East Asian
+// Wide/Fullwidth blocks (CJK, Hangul, Hiragana/Katakana, fullwidth forms).
+func isEastAsianWide(r rune) bool {
+	switch {
+	case r >= 0x1100 && r <= 0x115F,
+		r == 0x2329, r == 0x232A,
+		r >= 0x2E80 && r <= 0x303E,
+		r >= 0x3041 && r <= 0x33FF,
+		r >= 0x3400 && r <= 0x4DBF,
+		r >= 0x4E00 && r <= 0x9FFF,
+		r >= 0xA000 && r <= 0xA4CF,
+		r >= 0xAC00 && r <= 0xD7A3,
+		r >= 0xF900 && r <= 0xFAFF,
+		r >= 0xFE30 && r <= 0xFE4F,
+		r >= 0xFF00 && r <= 0xFF60,
+		r >= 0xFFE0 && r <= 0xFFE6,
+		r >= 0x20000 && r <= 0x3FFFD:
+		return true
+	}
+	return false
+}
+
+// wordWrap breaks s into lines no wider than width columns, breaking on
+// spaces; a single word wider than width is placed alone rather than cut.
+func wordWrap(s string, width int) []string {
+	if width < 1 {
+		width = 1
+	}
+	words := strings.Fields(s)
+	if len(words) == 0 {
+		return []string{""}
+	}
+	lines := make([]string, 0, 1)
+	cur := words[0]
+	curW := stringWidth(cur)
+	for _, w := range words[1:] {
+		ww := stringWidth(w)
+		if curW+1+ww <= width {
+			cur += " " + w
+			curW += 1 + ww
+		} else {
+			lines = append(lines, cur)
+			cur = w
+			curW = ww
+		}
+	}
+	lines = append(lines, cur)
+	return lines
+}
@@ -0,0 +1,39 @@
+package cmdp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_StringWidth_ASCII(t *testing.T) {
+	assrt := assert.New(t)
+	assrt.Equal(5, stringWidth("hello"))
+}
+
+func Test_StringWidth_EastAsianWideCountsDouble(t *testing.T) {
+	assrt := assert.New(t)
+	assrt.Equal(4, stringWidth("中文"))
+}
+
+func Test_StringWidth_CombiningMarkCountsZero(t *testing.T) {
+	assrt := assert.New(t)
+	assrt.Equal(1, stringWidth("é"))
+}
+
+func Test_StringWidth_StripsCSIEscapes(t *testing.T) {
+	assrt := assert.New(t)
+	assrt.Equal(3, stringWidth("\x1b[31mfoo\x1b[0m"))
+}
+
+func Test_WordWrap_BreaksOnSpaces(t *testing.T) {
+	assrt := assert.New(t)
+	lines := wordWrap("the quick brown fox", 10)
+	assrt.Equal([]string{"the quick", "brown fox"}, lines)
+}
+
+func Test_WordWrap_OversizeWordStandsAlone(t *testing.T) {
+	assrt := assert.New(t)
+	lines := wordWrap("supercalifragilisticexpialidocious word", 10)
+	assrt.Equal([]string{"supercalifragilisticexpialidocious", "word"}, lines)
+}
@@ -0,0 +1,98 @@
+package cmdp
+
+import (
+	"fmt"
+	"strings"
+)
+
+/*
+ParseShlex is a convenience function for commands whose arguments should be
+tokenized the way a POSIX shell tokenizes a command line: whitespace
+separates tokens, single quotes preserve everything literally, double
+quotes preserve whitespace but honor backslash escapes for ", \, $ and
+newline, and a backslash outside quotes escapes the next byte (a backslash
+at the very end of the line is a "dangling escape" error).
+It strips the command name - the first token, already normalized by cmdp -
+and returns the remaining tokens as args. Use it in place of ParseNone or a
+hand-rolled regex when a command accepts file paths or flags containing
+spaces.
+*/
+func ParseShlex() Parser {
+	return shlex{}
+}
+
+type shlex struct {
+}
+
+func (shlex) Parse(cmdln string) (args []string, err error) {
+	toks, err := shlexTokenize(cmdln)
+	if err != nil {
+		return nil, err
+	}
+	if len(toks) < 1 {
+		return nil, nil
+	}
+	return toks[1:], nil
+}
+
+func shlexTokenize(s string) (toks []string, err error) {
+	var cur strings.Builder
+	started := false
+	i := 0
+	n := len(s)
+	for i < n {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t':
+			if started {
+				toks = append(toks, cur.String())
+				cur.Reset()
+				started = false
+			}
+			i++
+		case c == '\'':
+			started = true
+			i++
+			start := i
+			for i < n && s[i] != '\'' {
+				i++
+			}
+			if i >= n {
+				return nil, fmt.Errorf("unterminated quote")
+			}
+			cur.WriteString(s[start:i])
+			i++
+		case c == '"':
+			started = true
+			i++
+			for i < n && s[i] != '"' {
+				if s[i] == '\\' && i+1 < n && strings.ContainsRune("\"\\$\n", rune(s[i+1])) {
+					cur.WriteByte(s[i+1])
+					i += 2
+					continue
+				}
+				cur.WriteByte(s[i])
+				i++
+			}
+			if i >= n {
+				return nil, fmt.Errorf("unterminated quote")
+			}
+			i++
+		case c == '\\':
+			started = true
+			if i+1 >= n {
+				return nil, fmt.Errorf("dangling escape")
+			}
+			cur.WriteByte(s[i+1])
+			i += 2
+		default:
+			started = true
+			cur.WriteByte(c)
+			i++
+		}
+	}
+	if started {
+		toks = append(toks, cur.String())
+	}
+	return toks, nil
+}
@@ -0,0 +1,49 @@
+package cmdp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ParseShlex_Basic(t *testing.T) {
+	assrt := assert.New(t)
+	args, err := ParseShlex().Parse("cp src dst")
+	assrt.Nil(err)
+	assrt.Equal([]string{"src", "dst"}, args)
+}
+
+func Test_ParseShlex_SingleQuotePreservesLiteral(t *testing.T) {
+	assrt := assert.New(t)
+	args, err := ParseShlex().Parse(`cp 'my file' dst`)
+	assrt.Nil(err)
+	assrt.Equal([]string{"my file", "dst"}, args)
+}
+
+func Test_ParseShlex_DoubleQuoteHonorsEscapes(t *testing.T) {
+	assrt := assert.New(t)
+	args, err := ParseShlex().Parse(`echo "a \"b\" $\\"`)
+	assrt.Nil(err)
+	assrt.Equal([]string{`a "b" $\`}, args)
+}
+
+func Test_ParseShlex_BackslashEscapesNextByte(t *testing.T) {
+	assrt := assert.New(t)
+	args, err := ParseShlex().Parse(`echo a\ b`)
+	assrt.Nil(err)
+	assrt.Equal([]string{"a b"}, args)
+}
+
+func Test_ParseShlex_UnterminatedQuote(t *testing.T) {
+	assrt := assert.New(t)
+	_, err := ParseShlex().Parse(`echo 'a`)
+	assrt.NotNil(err)
+	assrt.Contains(err.Error(), "unterminated quote")
+}
+
+func Test_ParseShlex_DanglingEscape(t *testing.T) {
+	assrt := assert.New(t)
+	_, err := ParseShlex().Parse(`echo a\`)
+	assrt.NotNil(err)
+	assrt.Contains(err.Error(), "dangling escape")
+}
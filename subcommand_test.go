@@ -0,0 +1,90 @@
+package cmdp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Validate_SubAndRunRejected(t *testing.T) {
+	assrt := assert.New(t)
+	_, err := validate([]Cdef{
+		{NmShort: "r", NmLong: "repo", Run: runNone{}, Help: "repo commands",
+			Sub: []Cdef{
+				{NmShort: "a", NmLong: "add", Run: runNone{}, Parse: ParseNone(), Help: "add a repo"},
+			}},
+	})
+	assrt.NotNil(err)
+	assrt.Contains(err.Error(), "must not specify both Sub and Run")
+}
+
+func Test_Validate_SiblingNamesUniquePerLevel(t *testing.T) {
+	assrt := assert.New(t)
+	_, err := validate([]Cdef{
+		{NmShort: "a", NmLong: "add", Run: runNone{}, Parse: ParseNone(), Help: "add at top"},
+		{NmLong: "repo", Help: "repo commands",
+			Sub: []Cdef{
+				{NmShort: "a", NmLong: "add", Run: runNone{}, Parse: ParseNone(), Help: "add a repo"},
+			}},
+	})
+	assrt.Nil(err)
+}
+
+func Test_Validate_DuplicateSiblingNameRejected(t *testing.T) {
+	assrt := assert.New(t)
+	_, err := validate([]Cdef{
+		{NmLong: "repo", Help: "repo commands",
+			Sub: []Cdef{
+				{NmShort: "a", NmLong: "add", Run: runNone{}, Parse: ParseNone(), Help: "add a repo"},
+				{NmShort: "a", NmLong: "append", Run: runNone{}, Parse: ParseNone(), Help: "append a repo"},
+			}},
+	})
+	assrt.NotNil(err)
+	assrt.Contains(err.Error(), "duplicate command name")
+}
+
+func Test_CmdSelect_DescendsToLeaf(t *testing.T) {
+	assrt := assert.New(t)
+	cs, err := validate([]Cdef{
+		{NmLong: "repo", Help: "repo commands",
+			Sub: []Cdef{
+				{NmShort: "a", NmLong: "add", Run: runNone{}, Parse: ParseNone(), Help: "add a repo"},
+			}},
+	})
+	assrt.Nil(err)
+	leaf, leafNm, leafArg, err := cmdSelect(cs.cmmds, "repo", "add origin")
+	assrt.Nil(err)
+	assrt.Equal("add", leafNm)
+	assrt.Equal("origin", leafArg)
+	assrt.Equal("add", leaf.NmLong)
+}
+
+func Test_Validate_PatchesHelpRunnerNestedUnderSub(t *testing.T) {
+	assrt := assert.New(t)
+	cs, err := validate([]Cdef{
+		{NmLong: "repo", Help: "repo commands",
+			Sub: []Cdef{
+				{NmShort: "h", NmLong: "help", Run: RunHelp(), Parse: ParseNone(), Help: "repo help"},
+			}},
+	})
+	assrt.Nil(err)
+	leaf, _, _, err := cmdSelect(cs.cmmds, "repo", "help")
+	assrt.Nil(err)
+	hlp := leaf.Run.(*helpRunner)
+	assrt.NotNil(hlp.cs)
+	assrt.Nil(hlp.Run(nil))
+}
+
+func Test_CmdSelect_UnknownSubcommand(t *testing.T) {
+	assrt := assert.New(t)
+	cs, err := validate([]Cdef{
+		{NmLong: "repo", Help: "repo commands",
+			Sub: []Cdef{
+				{NmShort: "a", NmLong: "add", Run: runNone{}, Parse: ParseNone(), Help: "add a repo"},
+			}},
+	})
+	assrt.Nil(err)
+	_, _, _, err = cmdSelect(cs.cmmds, "repo", "bogus")
+	assrt.NotNil(err)
+	assrt.Contains(err.Error(), "unknown subcommand 'bogus' under 'repo'")
+}